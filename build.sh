@@ -2,4 +2,9 @@
 
 set -e
 
-go build -o pomo
+VERSION=${VERSION:-$(git describe --tags --always --dirty 2>/dev/null || echo dev)}
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo none)
+BUILD_DATE=$(date -u +%Y-%m-%d)
+PKG=github.com/pranavek/pomodoro/cmd
+
+go build -ldflags "-X $PKG.Version=$VERSION -X $PKG.Commit=$COMMIT -X $PKG.BuildDate=$BUILD_DATE" -o pomo
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pranavek/pomodoro/pomo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupOutput string
+	restoreInput string
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Save the pomo data files to a ZIP archive",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifest, err := pomo.Backup(backupOutput)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Backed up to", backupOutput)
+		printManifest(manifest)
+		return nil
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the pomo data files from a backup archive",
+	Long: `Restore the pomo data files from an archive made by pomo backup.
+
+Files already in ~/.pomo are kept alongside the restored ones with a
+timestamped .bak suffix.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifest, err := pomo.Restore(restoreInput)
+		printManifest(manifest)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Restored from", restoreInput)
+		return nil
+	},
+}
+
+func printManifest(manifest []pomo.BackupFile) {
+	for _, file := range manifest {
+		fmt.Printf("  %-12s %8d bytes\n", file.Name, file.Size)
+	}
+}
+
+func init() {
+	backupCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "archive file to write")
+	backupCmd.MarkFlagRequired("output")
+	restoreCmd.Flags().StringVarP(&restoreInput, "input", "i", "", "archive file to read")
+	restoreCmd.MarkFlagRequired("input")
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
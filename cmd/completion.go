@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for pomo.
+
+Bash:
+  $ source <(pomo completion bash)
+  # To load completions for every session, on Linux:
+  $ pomo completion bash > /etc/bash_completion.d/pomo
+  # on macOS:
+  $ pomo completion bash > $(brew --prefix)/etc/bash_completion.d/pomo
+
+Zsh:
+  # Enable completion once if it is not already:
+  $ echo "autoload -U compinit; compinit" >> ~/.zshrc
+  $ pomo completion zsh > "${fpath[1]}/_pomo"
+  # Start a new shell for this to take effect.
+
+Fish:
+  $ pomo completion fish | source
+  # To load completions for every session:
+  $ pomo completion fish > ~/.config/fish/completions/pomo.fish
+
+PowerShell:
+  PS> pomo completion powershell | Out-String | Invoke-Expression
+  # To load completions for every session, add the output to your profile:
+  PS> pomo completion powershell >> $PROFILE
+`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.ExactValidArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
+
+// fixedCompletion completes a flag or argument from a static list.
+func fixedCompletion(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
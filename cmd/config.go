@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pranavek/pomodoro/pomo"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage persistent settings in ~/.pomo/config.toml or config.yaml",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the current configuration",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := pomo.LoadConfig()
+		if err != nil {
+			return err
+		}
+		fmt.Println("Configuration")
+		fmt.Printf("  work_duration:         %v\n", cfg.WorkDuration)
+		fmt.Printf("  short_break:           %v\n", cfg.ShortBreak)
+		fmt.Printf("  long_break:            %v\n", cfg.LongBreak)
+		fmt.Printf("  long_break_interval:   %d\n", cfg.LongBreakInterval)
+		fmt.Printf("  db_path:               %s (unused)\n", cfg.DBPath)
+		fmt.Printf("  sound_enabled:         %t\n", cfg.SoundEnabled)
+		fmt.Printf("  notifications_enabled: %t\n", cfg.NotificationsEnabled)
+		fmt.Printf("  auto_start:            %t\n", cfg.AutoStart)
+		fmt.Printf("  reminders:             %t\n", cfg.Reminders)
+		fmt.Printf("  on_pomodoro:           %s\n", cfg.OnPomodoro)
+		fmt.Printf("  on_break:              %s\n", cfg.OnBreak)
+		fmt.Printf("  webhook.url:           %s\n", cfg.Webhook.URL)
+		fmt.Printf("  webhook.secret:        %s\n", maskSecret(cfg.Webhook.Secret))
+		fmt.Printf("  webhook.enabled:       %t\n", cfg.Webhook.Enabled)
+		fmt.Printf("  webhook.timeout:       %v\n", cfg.Webhook.Timeout)
+		fmt.Printf("  slack.webhook:         %s\n", maskSecret(cfg.Slack.Webhook))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Change a setting",
+	Long: "Change a setting. The config file is rewritten, so comments in it are lost.\n\n" +
+		"Valid keys: " + strings.Join(pomo.ConfigKeys(), ", "),
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return pomo.ConfigKeys(), cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := pomo.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := pomo.SaveConfig(cfg); err != nil {
+			return err
+		}
+		value := args[1]
+		if pomo.IsSecretConfigKey(args[0]) {
+			value = maskSecret(value)
+		}
+		fmt.Printf("%s set to %s\n", args[0], value)
+		return nil
+	},
+}
+
+var (
+	configInitFormat string
+	configInitForce  bool
+)
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented config file with the default settings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := pomo.WriteConfigTemplate(configInitFormat, configInitForce)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Wrote", path)
+		return nil
+	},
+}
+
+// maskSecret hides all but the last four characters of secret.
+func maskSecret(secret string) string {
+	if len(secret) <= 4 {
+		return strings.Repeat("*", len(secret))
+	}
+	return strings.Repeat("*", len(secret)-4) + secret[len(secret)-4:]
+}
+
+func init() {
+	configInitCmd.Flags().StringVar(&configInitFormat, "format", "yaml", "config file format: toml or yaml")
+	configInitCmd.RegisterFlagCompletionFunc("format", fixedCompletion("toml", "yaml"))
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "overwrite an existing config file")
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configInitCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pranavek/pomodoro/pomo"
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common configuration and state problems",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		failed := false
+		for _, check := range pomo.RunChecks() {
+			if check.Err != nil && doctorFix && check.Fix != nil {
+				if err := check.Fix(); err != nil {
+					fmt.Printf("✗ %s: %v (fix failed: %v)\n", check.Name, check.Err, err)
+					failed = true
+				} else {
+					fmt.Printf("✓ %s (fixed: %v)\n", check.Name, check.Err)
+				}
+				continue
+			}
+			if check.Err != nil {
+				hint := ""
+				if check.Fix != nil {
+					hint = " (run pomo doctor --fix)"
+				}
+				fmt.Printf("✗ %s: %v%s\n", check.Name, check.Err, hint)
+				failed = true
+				continue
+			}
+			fmt.Printf("✓ %s\n", check.Name)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "attempt to repair the problems found")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pranavek/pomodoro/pomo"
+	"github.com/spf13/cobra"
+)
+
+var notifyTestSlack bool
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage notifications sent when a session ends",
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test message to check a notification is set up",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !notifyTestSlack {
+			return errors.New("choose a notification to test, e.g. --slack")
+		}
+		cfg, err := pomo.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if cfg.Slack.Webhook == "" {
+			return errors.New("slack.webhook is not set, run pomo config set slack.webhook URL")
+		}
+		notifier := pomo.SlackNotifier{WebhookURL: cfg.Slack.Webhook, Timeout: cfg.Webhook.Timeout}
+		err = notifier.Notify(pomo.SessionRecord{
+			Date:  time.Now(),
+			Title: "pomo test message",
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println("Test message sent to Slack")
+		return nil
+	},
+}
+
+func init() {
+	notifyTestCmd.Flags().BoolVar(&notifyTestSlack, "slack", false, "send a test message to the Slack webhook")
+	notifyCmd.AddCommand(notifyTestCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
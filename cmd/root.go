@@ -1,19 +1,199 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/pranavek/pomodoro/pomo"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	flagConfig = pomo.DefaultTimerConfig()
+	noProgress bool
+	noColor    bool
+	noSound    bool
+	noNotify   bool
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "pomo",
 	Short: "Pomo helps to implement pomodoro in your workflow",
-	Run: func(cmd *cobra.Command, args []string) {
-		pomo.Run()
+	Long: `Pomo helps to implement pomodoro in your workflow.
+
+After every break pomo asks whether to start another pomodoro. With
+--count N it stops after N completed pomodoros instead of asking. With
+--auto it never asks and runs until interrupted with Ctrl-C; combined
+with --count it still stops after N pomodoros.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if noColor {
+			pomo.ColorEnabled = false
+		}
 	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := pomo.LoadConfig()
+		if errors.Is(err, pomo.ErrNoPomoDir) {
+			// The timer itself does not need ~/.pomo.
+			fmt.Fprintln(os.Stderr, "warning: using the default settings:", err)
+			config, err = pomo.DefaultConfig(), nil
+		}
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		// Check before prompting so the answers are not asked for in vain.
+		if pomo.TimerRunning() {
+			return pomo.ErrTimerRunning
+		}
+		cfg := config.TimerConfig()
+		applyTimerFlags(cmd, &cfg)
+		if cfg.Resume, err = promptResume(); err != nil {
+			return err
+		}
+		if cfg.Resume == nil {
+			if err := promptSessionDetails(cmd, &cfg); err != nil {
+				return err
+			}
+		}
+		return pomo.RunWithConfig(cfg)
+	},
+}
+
+// applyTimerFlags overrides the saved config with any flags given explicitly
+// and copies in the per-session settings.
+func applyTimerFlags(cmd *cobra.Command, cfg *pomo.TimerConfig) {
+	flags := cmd.Flags()
+	if flags.Changed("work") {
+		cfg.WorkDuration = flagConfig.WorkDuration
+	}
+	if flags.Changed("short-break") {
+		cfg.ShortBreak = flagConfig.ShortBreak
+	}
+	if flags.Changed("long-break") {
+		cfg.LongBreak = flagConfig.LongBreak
+	}
+	if flags.Changed("interval") || flags.Changed("long-break-interval") {
+		cfg.LongBreakInterval = flagConfig.LongBreakInterval
+	}
+	if flags.Changed("no-sound") {
+		cfg.SoundEnabled = !noSound
+	}
+	if flags.Changed("no-notify") {
+		cfg.NotificationsEnabled = !noNotify
+	}
+	if flags.Changed("auto") {
+		cfg.AutoStart = flagConfig.AutoStart
+	}
+	if flags.Changed("reminders") {
+		cfg.Reminders = flagConfig.Reminders
+	}
+	if flags.Changed("on-pomodoro") {
+		cfg.OnPomodoro = flagConfig.OnPomodoro
+	}
+	if flags.Changed("on-break") {
+		cfg.OnBreak = flagConfig.OnBreak
+	}
+	cfg.Title = flagConfig.Title
+	cfg.Goal = flagConfig.Goal
+	cfg.Count = flagConfig.Count
+	cfg.ShowProgress = !noProgress && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// promptSessionDetails asks for a title and goal when they were not given
+// as flags and stdin is a terminal. Empty answers are allowed.
+func promptSessionDetails(cmd *cobra.Command, cfg *pomo.TimerConfig) error {
+	if pomo.QuietMode || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var err error
+	if !cmd.Flags().Changed("title") {
+		if cfg.Title, err = promptLine(reader, "Session title: "); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("goal") {
+		if cfg.Goal, err = promptLine(reader, "Session goal: "); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promptResume offers to pick up a session that was interrupted by a crash
+// or sleep. Declining discards it. Without a terminal to ask on, the new
+// session replaces it, so a warning says it was discarded.
+func promptResume() (*pomo.SessionState, error) {
+	state, err := pomo.InterruptedSession()
+	if errors.Is(err, pomo.ErrNoPomoDir) {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: ignoring unreadable session state:", err)
+		return nil, nil
+	}
+	if state == nil {
+		return nil, nil
+	}
+	if pomo.QuietMode || !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "warning: discarding the session interrupted at %s\n",
+			state.UpdatedAt.Format("2006-01-02 15:04"))
+		return nil, pomo.ClearSessionState()
+	}
+
+	where := fmt.Sprintf("%v into a %s", state.Elapsed.Round(time.Second), state.Phase)
+	if state.Phase == pomo.PhaseWaiting {
+		where = "between pomodoros"
+	}
+	fmt.Printf("Found an interrupted session from %s (%d pomodoros done, %s).\n",
+		state.UpdatedAt.Format("2006-01-02 15:04"), state.Record.CompletedPomos, where)
+	answer, err := promptLine(bufio.NewReader(os.Stdin), "Resume it? [Y/n] ")
+	if err != nil {
+		return nil, err
+	}
+	if answer == "" || strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes") {
+		return state, nil
+	}
+	return nil, pomo.ClearSessionState()
+}
+
+func promptLine(reader *bufio.Reader, label string) (string, error) {
+	fmt.Print(label)
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&pomo.QuietMode, "quiet", "q", false, "suppress all terminal output, sounds and notifications")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable coloured output (also honours NO_COLOR)")
+
+	flags := rootCmd.Flags()
+	flags.DurationVar(&flagConfig.WorkDuration, "work", flagConfig.WorkDuration, "length of a pomodoro work interval")
+	flags.DurationVar(&flagConfig.ShortBreak, "short-break", flagConfig.ShortBreak, "length of a short break")
+	flags.DurationVar(&flagConfig.LongBreak, "long-break", flagConfig.LongBreak, "length of a long break")
+	flags.IntVar(&flagConfig.LongBreakInterval, "interval", flagConfig.LongBreakInterval, "number of pomodoros before a long break")
+	flags.IntVar(&flagConfig.LongBreakInterval, "long-break-interval", flagConfig.LongBreakInterval, "same as --interval")
+	flags.BoolVar(&noProgress, "no-progress", false, "do not show the live countdown")
+	flags.StringVar(&flagConfig.Title, "title", "", "title of this session (prompted for when omitted)")
+	flags.BoolVar(&noSound, "no-sound", false, "do not beep at the start and end of intervals")
+	flags.BoolVar(&noNotify, "no-notify", false, "do not show desktop notifications")
+	flags.StringVar(&flagConfig.OnPomodoro, "on-pomodoro", "", "shell command to run when a pomodoro finishes")
+	flags.StringVar(&flagConfig.OnBreak, "on-break", "", "shell command to run when a break finishes")
+	flags.BoolVar(&flagConfig.AutoStart, "auto", false, "start each pomodoro after its break without asking")
+	flags.BoolVar(&flagConfig.Reminders, "reminders", false, "notify halfway through and 5 minutes before the end of a pomodoro")
+	flags.IntVar(&flagConfig.Count, "count", 0, "stop after N pomodoros instead of asking after each break")
+	flags.StringVar(&flagConfig.Goal, "goal", "", "goal of this session (prompted for when omitted)")
 }
 
 func Execute() {
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pranavek/pomodoro/pomo"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running pomodoro timer's phase and remaining time",
+	Long: `Show the running pomodoro timer's phase and remaining time.
+
+The output is a single line, suitable for tmux or polybar status lines.
+When no timer is running pomo status says so and still exits 0.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state, err := pomo.ActiveSession()
+		if err != nil {
+			return err
+		}
+		if state == nil {
+			fmt.Println("no pomodoro running")
+			return nil
+		}
+		fmt.Println(state.Status(time.Now()))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+)
+
+// Set at build time, e.g.
+// go build -ldflags "-X github.com/pranavek/pomodoro/cmd.Version=v1.2.3"
+var (
+	Version   = "dev"
+	Commit    = "none"
+	BuildDate = "unknown"
+)
+
+var versionFormat string
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version and build information",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch versionFormat {
+		case "text":
+			fmt.Printf("pomo %s\n", Version)
+			fmt.Printf("  commit: %s\n", Commit)
+			fmt.Printf("  built:  %s\n", BuildDate)
+			fmt.Printf("  go:     %s\n", runtime.Version())
+		case "json":
+			out, err := json.Marshal(struct {
+				Version string `json:"version"`
+				Commit  string `json:"commit"`
+				Built   string `json:"built"`
+				Go      string `json:"go"`
+			}{Version, Commit, BuildDate, runtime.Version()})
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+		default:
+			return fmt.Errorf("unknown format %q (want text or json)", versionFormat)
+		}
+		return nil
+	},
+}
+
+func init() {
+	versionCmd.Flags().StringVar(&versionFormat, "format", "text", "output format: text or json")
+	versionCmd.RegisterFlagCompletionFunc("format", fixedCompletion("text", "json"))
+	rootCmd.AddCommand(versionCmd)
+}
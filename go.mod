@@ -2,15 +2,20 @@ module github.com/pranavek/pomodoro
 
 go 1.17
 
-require github.com/spf13/cobra v1.4.0
+require (
+	github.com/BurntSushi/toml v1.2.0
+	github.com/gen2brain/beeep v0.0.0-20220518085355-d7852edf42fc
+	github.com/spf13/cobra v1.4.0
+	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a
+	golang.org/x/term v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
-	github.com/gen2brain/beeep v0.0.0-20220518085355-d7852edf42fc // indirect
 	github.com/go-toast/toast v0.0.0-20190211030409-01e6764cf0a4 // indirect
 	github.com/godbus/dbus/v5 v5.1.0 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/nu7hatch/gouuid v0.0.0-20131221200532-179d4d0c4d8d // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/tadvi/systray v0.0.0-20190226123456-11a2b8fa57af // indirect
-	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
 )
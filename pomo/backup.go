@@ -0,0 +1,182 @@
+package pomo
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// backupFiles are the files in ~/.pomo copied by Backup and accepted by
+// Restore. Missing files are skipped.
+var backupFiles = []string{
+	"pomo.db",
+	goalsFileName,
+	configFileName,
+	configTOMLFileName,
+	timerConfigFileName,
+}
+
+// BackupFile is one entry in the manifest printed by backup and restore.
+type BackupFile struct {
+	Name string
+	Size int64
+}
+
+// Backup writes a ZIP archive of the pomo data files to output. The config
+// may hold webhook credentials, so the archive is readable by the owner
+// only.
+func Backup(output string) ([]BackupFile, error) {
+	dir, err := pomoDir()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	archive := zip.NewWriter(f)
+	var manifest []BackupFile
+	for _, name := range backupFiles {
+		size, err := addToArchive(archive, filepath.Join(dir, name), name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("backing up %s: %w", name, err)
+		}
+		manifest = append(manifest, BackupFile{Name: name, Size: size})
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if len(manifest) == 0 {
+		os.Remove(output)
+		return nil, fmt.Errorf("nothing to back up in %s", dir)
+	}
+	return manifest, nil
+}
+
+func addToArchive(archive *zip.Writer, path, name string) (int64, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return 0, err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return 0, err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	dst, err := archive.CreateHeader(header)
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(dst, src)
+}
+
+// Restore extracts a backup archive made by Backup into ~/.pomo. Entries
+// are extracted to temporary files first, so a damaged archive leaves the
+// existing files untouched. Existing files are then renamed with a
+// timestamp suffix, such as config.yaml.20060102-150405.bak, and replaced.
+// Entries that Backup never writes are rejected so an archive cannot place
+// files elsewhere.
+func Restore(input string) ([]BackupFile, error) {
+	dir, err := pomoDir()
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.OpenReader(input)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	seen := make(map[string]bool)
+	for _, entry := range archive.File {
+		if !isBackupFile(entry.Name) || seen[entry.Name] {
+			return nil, fmt.Errorf("unexpected file %q in backup", entry.Name)
+		}
+		seen[entry.Name] = true
+	}
+
+	temps := make([]string, len(archive.File))
+	defer func() {
+		// Only the files not moved into place are left to remove.
+		for _, temp := range temps {
+			if temp != "" {
+				os.Remove(temp)
+			}
+		}
+	}()
+	sizes := make([]int64, len(archive.File))
+	for i, entry := range archive.File {
+		temps[i], sizes[i], err = extractTemp(entry, dir)
+		if err != nil {
+			return nil, fmt.Errorf("restoring %s: %w", entry.Name, err)
+		}
+	}
+
+	suffix := time.Now().Format("20060102-150405") + ".bak"
+	var manifest []BackupFile
+	for i, entry := range archive.File {
+		path := filepath.Join(dir, entry.Name)
+		err := os.Rename(path, path+"."+suffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return manifest, fmt.Errorf("keeping existing %s: %w", entry.Name, err)
+		}
+		if err := os.Rename(temps[i], path); err != nil {
+			return manifest, fmt.Errorf("restoring %s: %w", entry.Name, err)
+		}
+		temps[i] = ""
+		manifest = append(manifest, BackupFile{Name: entry.Name, Size: sizes[i]})
+	}
+	return manifest, nil
+}
+
+func isBackupFile(name string) bool {
+	for _, known := range backupFiles {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// extractTemp extracts entry to a new file in dir readable by the owner
+// only, and returns its path.
+func extractTemp(entry *zip.File, dir string) (string, int64, error) {
+	src, err := entry.Open()
+	if err != nil {
+		return "", 0, err
+	}
+	defer src.Close()
+	dst, err := os.CreateTemp(dir, "."+entry.Name+".restore-*")
+	if err != nil {
+		return "", 0, err
+	}
+	size, err := io.Copy(dst, src)
+	if err == nil {
+		err = dst.Close()
+	} else {
+		dst.Close()
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", size, err
+	}
+	return dst.Name(), size, nil
+}
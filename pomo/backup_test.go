@@ -0,0 +1,111 @@
+package pomo
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setTestHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	dir, err := pomoDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestBackupRestore(t *testing.T) {
+	dir := setTestHome(t)
+	files := map[string]string{
+		configFileName:      "work_duration: 50m0s\n",
+		timerConfigFileName: `{"work_duration": "50m0s"}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive := filepath.Join(t.TempDir(), "pomo.zip")
+	manifest, err := Backup(archive)
+	if err != nil {
+		t.Fatalf("Backup() = %v", err)
+	}
+	if len(manifest) != len(files) {
+		t.Errorf("Backup() manifest = %v, want %d files", manifest, len(files))
+	}
+
+	// Change a file so the restore has something to replace.
+	configPath := filepath.Join(dir, configFileName)
+	if err := os.WriteFile(configPath, []byte("changed\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(archive); err != nil {
+		t.Fatalf("Restore() = %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("restored %s = %q, want %q", name, got, want)
+		}
+	}
+	kept, _ := filepath.Glob(configPath + ".*.bak")
+	if len(kept) != 1 {
+		t.Fatalf("found backups %v of the replaced config, want 1", kept)
+	}
+	if got, _ := os.ReadFile(kept[0]); string(got) != "changed\n" {
+		t.Errorf("kept config = %q, want the replaced contents", got)
+	}
+}
+
+func TestRestoreRejectsUnexpectedFiles(t *testing.T) {
+	for _, name := range []string{"../evil.sh", "sub/" + configFileName, "notes.txt"} {
+		dir := setTestHome(t)
+		configPath := filepath.Join(dir, configFileName)
+		if err := os.WriteFile(configPath, []byte("original\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		archive := writeTestZip(t, map[string]string{
+			configFileName: "restored\n",
+			name:           "payload",
+		})
+
+		_, err := Restore(archive)
+		if err == nil || !strings.Contains(err.Error(), "unexpected file") {
+			t.Errorf("Restore() with %q = %v, want an unexpected file error", name, err)
+		}
+		if got, _ := os.ReadFile(configPath); string(got) != "original\n" {
+			t.Errorf("config after a rejected restore = %q, want it untouched", got)
+		}
+	}
+}
+
+func writeTestZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, data := range files {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Write([]byte(data))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package pomo
+
+import "errors"
+
+// Keys are still read on other platforms, but only once Enter is pressed.
+func enableCbreak(fd int) (restore func(), err error) {
+	return nil, errors.New("cbreak mode is not supported on this platform")
+}
+
+// inForeground is always true on platforms without job control.
+func inForeground(fd int) bool {
+	return true
+}
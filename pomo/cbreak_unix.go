@@ -0,0 +1,33 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package pomo
+
+import "golang.org/x/sys/unix"
+
+// enableCbreak switches the terminal on fd to unbuffered, unechoed input so
+// single keystrokes can be read without Enter. Unlike raw mode it keeps
+// signal generation, so Ctrl-C still interrupts the timer.
+func enableCbreak(fd int) (restore func(), err error) {
+	old, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+	state := *old
+	state.Lflag &^= unix.ICANON | unix.ECHO
+	state.Cc[unix.VMIN] = 1
+	state.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &state); err != nil {
+		return nil, err
+	}
+	return func() {
+		unix.IoctlSetTermios(fd, ioctlSetTermios, old)
+	}, nil
+}
+
+// inForeground reports whether this process owns the terminal on fd, so
+// reading from it or changing its mode will not stop the process.
+func inForeground(fd int) bool {
+	pgrp, err := unix.IoctlGetInt(fd, unix.TIOCGPGRP)
+	return err == nil && pgrp == unix.Getpgrp()
+}
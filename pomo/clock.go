@@ -0,0 +1,23 @@
+package pomo
+
+import "time"
+
+// Clock abstracts the passage of time so the timer can be driven by a fake
+// clock in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// RealClock is the Clock backed by the time package.
+var RealClock Clock = realClock{}
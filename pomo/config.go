@@ -0,0 +1,373 @@
+package pomo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configFileName     = "config.yaml"
+	configTOMLFileName = "config.toml"
+)
+
+// Config holds the persistent settings kept in ~/.pomo/config.toml or
+// ~/.pomo/config.yaml. Command-line flags override it, and it overrides the
+// built-in defaults.
+type Config struct {
+	WorkDuration         time.Duration `yaml:"work_duration" toml:"work_duration"`
+	ShortBreak           time.Duration `yaml:"short_break" toml:"short_break"`
+	LongBreak            time.Duration `yaml:"long_break" toml:"long_break"`
+	LongBreakInterval    int           `yaml:"long_break_interval" toml:"long_break_interval"`
+	DBPath               string        `yaml:"db_path,omitempty" toml:"db_path,omitempty"`
+	SoundEnabled         bool          `yaml:"sound_enabled" toml:"sound_enabled"`
+	NotificationsEnabled bool          `yaml:"notifications_enabled" toml:"notifications_enabled"`
+	AutoStart            bool          `yaml:"auto_start" toml:"auto_start"`
+	Reminders            bool          `yaml:"reminders" toml:"reminders"`
+	OnPomodoro           string        `yaml:"on_pomodoro,omitempty" toml:"on_pomodoro,omitempty"`
+	OnBreak              string        `yaml:"on_break,omitempty" toml:"on_break,omitempty"`
+	Webhook              WebhookConfig `yaml:"webhook" toml:"webhook"`
+	Slack                SlackConfig   `yaml:"slack" toml:"slack"`
+}
+
+func DefaultConfig() *Config {
+	timer := DefaultTimerConfig()
+	return &Config{
+		WorkDuration:         timer.WorkDuration,
+		ShortBreak:           timer.ShortBreak,
+		LongBreak:            timer.LongBreak,
+		LongBreakInterval:    timer.LongBreakInterval,
+		SoundEnabled:         true,
+		NotificationsEnabled: true,
+	}
+}
+
+// TimerConfig returns the timer settings described by c.
+func (c *Config) TimerConfig() TimerConfig {
+	cfg := DefaultTimerConfig()
+	cfg.WorkDuration = c.WorkDuration
+	cfg.ShortBreak = c.ShortBreak
+	cfg.LongBreak = c.LongBreak
+	cfg.LongBreakInterval = c.LongBreakInterval
+	cfg.SoundEnabled = c.SoundEnabled
+	cfg.NotificationsEnabled = c.NotificationsEnabled
+	cfg.AutoStart = c.AutoStart
+	cfg.Reminders = c.Reminders
+	cfg.OnPomodoro = c.OnPomodoro
+	cfg.OnBreak = c.OnBreak
+	cfg.Webhook = c.Webhook
+	cfg.Slack = c.Slack
+	return cfg
+}
+
+func (c *Config) Validate() error {
+	return c.TimerConfig().Validate()
+}
+
+// configSetters maps the keys accepted by Set to functions parsing a value
+// into the matching field.
+var configSetters = map[string]func(c *Config, value string) error{
+	"work_duration":         durationSetter(func(c *Config) *time.Duration { return &c.WorkDuration }),
+	"short_break":           durationSetter(func(c *Config) *time.Duration { return &c.ShortBreak }),
+	"long_break":            durationSetter(func(c *Config) *time.Duration { return &c.LongBreak }),
+	"long_break_interval":   intSetter(func(c *Config) *int { return &c.LongBreakInterval }),
+	"db_path":               stringSetter(func(c *Config) *string { return &c.DBPath }),
+	"sound_enabled":         boolSetter(func(c *Config) *bool { return &c.SoundEnabled }),
+	"notifications_enabled": boolSetter(func(c *Config) *bool { return &c.NotificationsEnabled }),
+	"auto_start":            boolSetter(func(c *Config) *bool { return &c.AutoStart }),
+	"reminders":             boolSetter(func(c *Config) *bool { return &c.Reminders }),
+	"on_pomodoro":           stringSetter(func(c *Config) *string { return &c.OnPomodoro }),
+	"on_break":              stringSetter(func(c *Config) *string { return &c.OnBreak }),
+	"webhook.url":           stringSetter(func(c *Config) *string { return &c.Webhook.URL }),
+	"webhook.secret":        stringSetter(func(c *Config) *string { return &c.Webhook.Secret }),
+	"webhook.enabled":       boolSetter(func(c *Config) *bool { return &c.Webhook.Enabled }),
+	"webhook.timeout":       durationSetter(func(c *Config) *time.Duration { return &c.Webhook.Timeout }),
+	"slack.webhook":         stringSetter(func(c *Config) *string { return &c.Slack.Webhook }),
+}
+
+func durationSetter(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+func intSetter(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func boolSetter(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func stringSetter(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+// secretConfigKeys are the settings holding credentials.
+var secretConfigKeys = map[string]bool{
+	"webhook.secret": true,
+	"slack.webhook":  true,
+}
+
+// IsSecretConfigKey reports whether the setting named key holds a
+// credential that should not be shown in full.
+func IsSecretConfigKey(key string) bool {
+	return secretConfigKeys[key]
+}
+
+// ConfigKeys lists the keys accepted by Config.Set.
+func ConfigKeys() []string {
+	keys := make([]string, 0, len(configSetters))
+	for key := range configSetters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Set parses value into the setting named key.
+func (c *Config) Set(key, value string) error {
+	set, ok := configSetters[key]
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	if err := set(c, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return c.Validate()
+}
+
+func configPath(name string) (string, error) {
+	dir, err := pomoDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func fileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// activeConfigPath returns the config file in use. config.toml wins over
+// config.yaml; when neither exists the YAML path is returned. ambiguous
+// reports whether both files exist.
+func activeConfigPath() (path string, ambiguous bool, err error) {
+	tomlPath, err := configPath(configTOMLFileName)
+	if err != nil {
+		return "", false, err
+	}
+	yamlPath, err := configPath(configFileName)
+	if err != nil {
+		return "", false, err
+	}
+	hasTOML, err := fileExists(tomlPath)
+	if err != nil {
+		return "", false, err
+	}
+	hasYAML, err := fileExists(yamlPath)
+	if err != nil {
+		return "", false, err
+	}
+	if hasTOML {
+		return tomlPath, hasYAML, nil
+	}
+	return yamlPath, false, nil
+}
+
+func isTOML(path string) bool {
+	return filepath.Ext(path) == ".toml"
+}
+
+// LoadConfig reads ~/.pomo/config.toml or ~/.pomo/config.yaml. Without
+// either, durations saved in the older ~/.pomo/timer.json are used, and
+// otherwise the defaults.
+func LoadConfig() (*Config, error) {
+	path, ambiguous, err := activeConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if ambiguous {
+		fmt.Fprintf(os.Stderr, "warning: both %s and %s exist, using %s\n", configTOMLFileName, configFileName, configTOMLFileName)
+	}
+	if isTOML(path) {
+		return loadConfigTOML(path)
+	}
+	return loadConfigYAML(path)
+}
+
+func loadConfigTOML(path string) (*Config, error) {
+	cfg := DefaultConfig()
+	if _, err := toml.DecodeFile(path, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func loadConfigYAML(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return configFromTimerConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func configFromTimerConfig() (*Config, error) {
+	timer, err := LoadTimerConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg := DefaultConfig()
+	cfg.WorkDuration = timer.WorkDuration
+	cfg.ShortBreak = timer.ShortBreak
+	cfg.LongBreak = timer.LongBreak
+	cfg.LongBreakInterval = timer.LongBreakInterval
+	cfg.OnPomodoro = timer.OnPomodoro
+	cfg.OnBreak = timer.OnBreak
+	return cfg, nil
+}
+
+// SaveConfig writes cfg back to the config file in use, in its format. The
+// file may hold webhook credentials, so it is made readable by the owner
+// only.
+func SaveConfig(cfg *Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	path, _, err := activeConfigPath()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if isTOML(path) {
+		err = toml.NewEncoder(&buf).Encode(cfg)
+	} else {
+		err = yaml.NewEncoder(&buf).Encode(cfg)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file.
+	return os.Chmod(path, 0600)
+}
+
+const configTemplate = `{{c}} pomo configuration. Command-line flags override these settings.
+{{c}} pomo config set rewrites this file without these comments.
+
+{{c}} Length of a pomodoro work interval, e.g. 25m or 50m.
+work_duration{{=}}"25m0s"
+{{c}} Length of the break after each pomodoro.
+short_break{{=}}"5m0s"
+{{c}} Length of the break after every long_break_interval pomodoros.
+long_break{{=}}"30m0s"
+long_break_interval{{=}}4
+
+{{c}} Path of the session database. Reserved: pomo does not store sessions
+{{c}} yet, so this setting is currently unused.
+{{c}} db_path{{=}}"~/.pomo/pomo.db"
+
+{{c}} Set to false to silence the beep or the desktop notifications.
+sound_enabled{{=}}true
+notifications_enabled{{=}}true
+
+{{c}} Start the next pomodoro after each break without asking.
+auto_start{{=}}false
+
+{{c}} Notify at the halfway point and with 5 minutes left of longer pomodoros.
+reminders{{=}}false
+
+{{c}} Shell commands run when a pomodoro or a break finishes, but not when
+{{c}} it is skipped. They receive
+{{c}} POMO_PHASE, POMO_COUNT, POMO_TITLE and POMO_GOAL in the environment.
+{{c}} on_pomodoro{{=}}"notify-send 'Pomodoro done'"
+{{c}} on_break{{=}}"echo $POMO_PHASE >> ~/pomo.log"
+
+{{c}} A webhook is POSTed the session summary as JSON when a session ends,
+{{c}} signed with HMAC-SHA256 in the X-Pomo-Signature header when a secret
+{{c}} is set. Configure it with:
+{{c}}   pomo config set webhook.url https://example.com/pomo
+{{c}}   pomo config set webhook.secret SECRET
+{{c}}   pomo config set webhook.enabled true
+{{c}} The end of a session waits up to webhook.timeout (default 5s) for it.
+{{c}}
+{{c}} To post a summary to Slack, set an incoming webhook URL with
+{{c}} pomo config set slack.webhook URL and check it with
+{{c}} pomo notify test --slack.
+`
+
+// WriteConfigTemplate writes a commented default config file in format,
+// "toml" or "yaml", and returns its path. An existing file is only
+// replaced when force is set.
+func WriteConfigTemplate(format string, force bool) (string, error) {
+	var name, assign string
+	switch format {
+	case "toml":
+		name, assign = configTOMLFileName, " = "
+	case "yaml":
+		name, assign = configFileName, ": "
+	default:
+		return "", fmt.Errorf("unknown config format %q (want toml or yaml)", format)
+	}
+	path, err := configPath(name)
+	if err != nil {
+		return "", err
+	}
+	exists, err := fileExists(path)
+	if err != nil {
+		return "", err
+	}
+	if exists && !force {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+	template := strings.NewReplacer("{{c}}", "#", "{{=}}", assign).Replace(configTemplate)
+	if err := os.WriteFile(path, []byte(template), 0600); err != nil {
+		return "", err
+	}
+	// Like SaveConfig, keep the file private even when replacing one.
+	return path, os.Chmod(path, 0600)
+}
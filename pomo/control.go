@@ -0,0 +1,156 @@
+package pomo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const socketFileName = "timer.sock"
+
+const (
+	CommandPause  = "pause"
+	CommandResume = "resume"
+)
+
+var (
+	ErrNoTimer      = errors.New("no pomodoro timer is running")
+	ErrTimerRunning = errors.New("another pomodoro timer is already running")
+)
+
+type controlRequest struct {
+	command string
+	reply   chan error
+}
+
+type controlServer struct {
+	listener net.Listener
+	requests chan controlRequest
+	// closed stops pending requests from being delivered once the timer
+	// has stopped listening.
+	closed chan struct{}
+}
+
+func socketPath() (string, error) {
+	dir, err := pomoDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, socketFileName), nil
+}
+
+func listenControl() (*controlServer, error) {
+	path, err := socketPath()
+	if err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, ErrTimerRunning
+	}
+	// Left behind by a timer that did not shut down cleanly.
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	s := &controlServer{
+		listener: listener,
+		requests: make(chan controlRequest),
+		closed:   make(chan struct{}),
+	}
+	go s.serve()
+	return s, nil
+}
+
+func (s *controlServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *controlServer) handle(conn net.Conn) {
+	defer conn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	conn.SetDeadline(deadline)
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	req := controlRequest{
+		command: strings.TrimSpace(line),
+		reply:   make(chan error, 1),
+	}
+	// Give up with the client rather than deliver a command it no longer
+	// waits for.
+	select {
+	case s.requests <- req:
+	case <-s.closed:
+		fmt.Fprintln(conn, "error: timer is shutting down")
+		return
+	case <-time.After(time.Until(deadline)):
+		return
+	}
+	if err := <-req.reply; err != nil {
+		fmt.Fprintln(conn, "error:", err)
+		return
+	}
+	fmt.Fprintln(conn, "ok")
+}
+
+func (s *controlServer) Close() error {
+	close(s.closed)
+	return s.listener.Close()
+}
+
+// TimerRunning reports whether a timer is listening for commands.
+func TimerRunning() bool {
+	path, err := socketPath()
+	if err != nil {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// SendCommand asks the running timer to execute command.
+func SendCommand(command string) error {
+	path, err := socketPath()
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return ErrNoTimer
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	reply = strings.TrimSpace(reply)
+	if reply != "ok" {
+		return errors.New(strings.TrimPrefix(reply, "error: "))
+	}
+	return nil
+}
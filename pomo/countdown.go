@@ -0,0 +1,80 @@
+package pomo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timer counts down a single interval and can be paused, resumed and
+// skipped. Time spent paused does not count towards the interval.
+type Timer struct {
+	clock    Clock
+	duration time.Duration
+	elapsed  time.Duration
+	started  time.Time
+	paused   bool
+	skipped  bool
+}
+
+// NewTimer returns a running Timer for d.
+func NewTimer(d time.Duration) *Timer {
+	return NewTimerWithClock(d, RealClock)
+}
+
+// NewTimerWithClock returns a running Timer for d that reads time from clock.
+func NewTimerWithClock(d time.Duration, clock Clock) *Timer {
+	return &Timer{clock: clock, duration: d, started: clock.Now()}
+}
+
+func (t *Timer) Pause() {
+	if t.paused {
+		return
+	}
+	t.elapsed += t.clock.Now().Sub(t.started)
+	t.paused = true
+}
+
+func (t *Timer) Resume() {
+	if !t.paused || t.skipped {
+		return
+	}
+	t.started = t.clock.Now()
+	t.paused = false
+}
+
+// Skip abandons the interval, freezing its elapsed time.
+func (t *Timer) Skip() {
+	t.Pause()
+	t.skipped = true
+}
+
+func (t *Timer) Skipped() bool {
+	return t.skipped
+}
+
+func (t *Timer) Paused() bool {
+	return t.paused
+}
+
+// Elapsed returns the unpaused time run so far, capped at the duration.
+func (t *Timer) Elapsed() time.Duration {
+	elapsed := t.elapsed
+	if !t.paused {
+		elapsed += t.clock.Now().Sub(t.started)
+	}
+	if elapsed > t.duration {
+		return t.duration
+	}
+	return elapsed
+}
+
+func (t *Timer) Remaining() time.Duration {
+	return t.duration - t.Elapsed()
+}
+
+// formatClock formats d as MM:SS, rounding up to the next whole second so
+// the display never reads 00:00 while time remains.
+func formatClock(d time.Duration) string {
+	seconds := int((d + time.Second - 1) / time.Second)
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,124 @@
+package pomo
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when advance is called, firing the
+// After channels that fall due.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	// waits, when set, receives the duration of every After call, so a
+	// test can step in lockstep with the code using the clock.
+	waits chan time.Duration
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.mu.Unlock()
+	if c.waits != nil {
+		c.waits <- d
+	}
+	return ch
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)}
+}
+
+func TestTimerElapsed(t *testing.T) {
+	clock := newFakeClock()
+	timer := NewTimerWithClock(25*time.Minute, clock)
+
+	clock.advance(10 * time.Minute)
+	if got := timer.Elapsed(); got != 10*time.Minute {
+		t.Errorf("Elapsed() = %v, want 10m0s", got)
+	}
+	if got := timer.Remaining(); got != 15*time.Minute {
+		t.Errorf("Remaining() = %v, want 15m0s", got)
+	}
+
+	clock.advance(time.Hour)
+	if got := timer.Elapsed(); got != 25*time.Minute {
+		t.Errorf("Elapsed() past the end = %v, want it capped at 25m0s", got)
+	}
+	if got := timer.Remaining(); got != 0 {
+		t.Errorf("Remaining() past the end = %v, want 0s", got)
+	}
+}
+
+func TestTimerPauseResume(t *testing.T) {
+	clock := newFakeClock()
+	timer := NewTimerWithClock(25*time.Minute, clock)
+
+	clock.advance(5 * time.Minute)
+	timer.Pause()
+	if !timer.Paused() {
+		t.Fatal("Paused() = false after Pause")
+	}
+	clock.advance(time.Hour)
+	if got := timer.Elapsed(); got != 5*time.Minute {
+		t.Errorf("Elapsed() while paused = %v, want 5m0s", got)
+	}
+
+	// Pausing twice must not count the pause again.
+	timer.Pause()
+	timer.Resume()
+	if timer.Paused() {
+		t.Fatal("Paused() = true after Resume")
+	}
+	clock.advance(2 * time.Minute)
+	if got := timer.Elapsed(); got != 7*time.Minute {
+		t.Errorf("Elapsed() after resuming = %v, want 7m0s", got)
+	}
+}
+
+func TestTimerSkip(t *testing.T) {
+	clock := newFakeClock()
+	timer := NewTimerWithClock(25*time.Minute, clock)
+
+	clock.advance(3 * time.Minute)
+	timer.Skip()
+	if !timer.Skipped() {
+		t.Fatal("Skipped() = false after Skip")
+	}
+
+	// A skipped timer stays frozen, even when resumed.
+	timer.Resume()
+	clock.advance(10 * time.Minute)
+	if got := timer.Elapsed(); got != 3*time.Minute {
+		t.Errorf("Elapsed() after Skip = %v, want 3m0s", got)
+	}
+}
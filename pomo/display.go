@@ -0,0 +1,65 @@
+package pomo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const defaultProgressBarWidth = 20
+
+const (
+	ColorGreen  = "\033[32m"
+	ColorYellow = "\033[33m"
+	ColorBlue   = "\033[34m"
+	ColorCyan   = "\033[36m"
+
+	ansiReset = "\033[0m"
+)
+
+// ColorEnabled controls whether Colorize emits ANSI colours. It defaults to
+// true when stdout is a terminal and NO_COLOR (https://no-color.org) is not
+// set.
+var ColorEnabled = term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == ""
+
+// Colorize wraps text in the given ANSI colour when colour is enabled.
+func Colorize(text, color string) string {
+	if !ColorEnabled || color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+// RenderProgressBar draws how much of total has elapsed as a bar of width
+// cells followed by the percentage, e.g. "[████████░░░░░░░░░░░░] 40%".
+// A width of zero or less uses the default of 20.
+func RenderProgressBar(elapsed, total time.Duration, width int) string {
+	if width <= 0 {
+		width = defaultProgressBarWidth
+	}
+	ratio := 1.0
+	if total > 0 {
+		ratio = float64(elapsed) / float64(total)
+	}
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * float64(width))
+	return fmt.Sprintf("[%s%s] %d%%",
+		strings.Repeat("█", filled),
+		strings.Repeat("░", width-filled),
+		int(ratio*100))
+}
+
+// phaseColor is green while working and blue during breaks.
+func phaseColor(phase Phase) string {
+	if phase == PhaseWork {
+		return ColorGreen
+	}
+	return ColorBlue
+}
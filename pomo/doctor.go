@@ -0,0 +1,125 @@
+package pomo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// goalsFileName holds daily goals. pomo does not write it itself, but
+// doctor checks it and backup archives it when present.
+const goalsFileName = "goals.json"
+
+// Check is one diagnostic run by pomo doctor. Fix, when set, attempts to
+// repair the problem Err describes.
+type Check struct {
+	Name string
+	Err  error
+	Fix  func() error
+}
+
+// RunChecks diagnoses the ~/.pomo directory, the config files and the
+// timer state.
+func RunChecks() []Check {
+	return []Check{
+		checkPomoDir(),
+		checkConfig(),
+		checkTimerConfig(),
+		checkGoals(),
+		checkTimerState(),
+	}
+}
+
+func checkPomoDir() Check {
+	check := Check{Name: "~/.pomo exists and is writable"}
+	dir, err := pomoDir()
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	f.Close()
+	check.Err = os.Remove(f.Name())
+	return check
+}
+
+func checkConfig() Check {
+	check := Check{Name: "config file is valid"}
+	cfg, err := LoadConfig()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	check.Err = err
+	return check
+}
+
+func checkTimerConfig() Check {
+	check := Check{Name: "timer.json is valid"}
+	cfg, err := LoadTimerConfig()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	check.Err = err
+	return check
+}
+
+func checkGoals() Check {
+	check := Check{Name: "goals.json is valid JSON if it exists"}
+	dir, err := pomoDir()
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	data, err := os.ReadFile(filepath.Join(dir, goalsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return check
+	}
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	var goals interface{}
+	check.Err = json.Unmarshal(data, &goals)
+	return check
+}
+
+func checkTimerState() Check {
+	check := Check{Name: "session state is current"}
+	state, err := LoadSessionState()
+	if err != nil {
+		check.Err = fmt.Errorf("corrupt state file: %w", err)
+		check.Fix = clearStaleTimer
+		return check
+	}
+	if state != nil && !TimerRunning() {
+		check.Err = errors.New("interrupted session found, run pomo to resume it or --fix to discard it")
+		check.Fix = clearStaleTimer
+	}
+	return check
+}
+
+// clearStaleTimer removes the state file, and the control socket unless a
+// live timer still answers on it.
+func clearStaleTimer() error {
+	if err := ClearSessionState(); err != nil {
+		return err
+	}
+	if TimerRunning() {
+		return nil
+	}
+	dir, err := pomoDir()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(dir, socketFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package pomo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// runHook starts command through the shell without waiting for it. The
+// hook sees POMO_PHASE (the phase that just finished), POMO_COUNT (pomodoros
+// completed this session), POMO_TITLE and POMO_GOAL in its environment.
+// Failures are reported as warnings and never stop the timer.
+func (s *session) runHook(command string, phase Phase) {
+	if command == "" {
+		return
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(),
+		"POMO_PHASE="+string(phase),
+		"POMO_COUNT="+strconv.Itoa(s.record.CompletedPomos),
+		"POMO_TITLE="+s.record.Title,
+		"POMO_GOAL="+s.record.Goal,
+	)
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: hook %q failed to start: %v\n", command, err)
+		return
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: hook %q failed: %v\n", command, err)
+		}
+	}()
+}
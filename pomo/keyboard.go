@@ -0,0 +1,45 @@
+package pomo
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	keyPause = 'p'
+	keySkip  = 's'
+)
+
+// listenKeys reads keystrokes from stdin in the background. keys is nil in
+// quiet mode and when stdin is not a terminal in the foreground, where
+// reading it would stop a backgrounded pomo. echo reports whether typed
+// keys must be echoed by the caller. restore restores the terminal and
+// must be called before exiting.
+func listenKeys() (keys <-chan byte, echo bool, restore func()) {
+	fd := int(os.Stdin.Fd())
+	if QuietMode || !term.IsTerminal(fd) || !inForeground(fd) {
+		return nil, false, func() {}
+	}
+	restore, err := enableCbreak(fd)
+	if err != nil {
+		// The terminal still echoes, and keys arrive once Enter is pressed.
+		restore = func() {}
+	}
+	ch := make(chan byte)
+	go readKeys(os.Stdin, ch)
+	return ch, err == nil, restore
+}
+
+// readKeys closes keys once r is exhausted.
+func readKeys(r io.Reader, keys chan<- byte) {
+	defer close(keys)
+	buf := make([]byte, 1)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			return
+		}
+		keys <- buf[0]
+	}
+}
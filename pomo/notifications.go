@@ -0,0 +1,105 @@
+package pomo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SlackConfig holds the Slack incoming webhook URL posted to when a
+// session ends. An empty URL turns Slack off.
+type SlackConfig struct {
+	Webhook string `yaml:"webhook,omitempty" toml:"webhook,omitempty"`
+}
+
+// SlackNotifier posts session summaries to a Slack incoming webhook.
+// Timeout bounds each post, and defaults to the webhook's default.
+type SlackNotifier struct {
+	WebhookURL string
+	Timeout    time.Duration
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackMessage struct {
+	// Text is shown in notifications and by clients without Block Kit.
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackMessageFor builds a Block Kit message summarising record.
+func slackMessageFor(record SessionRecord) slackMessage {
+	summary := fmt.Sprintf("Pomodoro session finished: %d pomodoros, %v of work",
+		record.CompletedPomos, record.WorkTime.Round(time.Second))
+	heading := "*Pomodoro session finished*"
+	if record.Title != "" {
+		heading = fmt.Sprintf("*%s* finished", escapeSlack(record.Title))
+	}
+	fields := []slackText{
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Pomodoros*\n%d", record.CompletedPomos)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Work time*\n%v", record.WorkTime.Round(time.Second))},
+	}
+	if record.Goal != "" {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*Goal*\n" + escapeSlack(record.Goal)})
+	}
+	return slackMessage{
+		Text: summary,
+		Blocks: []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: heading}},
+			{Type: "section", Fields: fields},
+		},
+	}
+}
+
+// escapeSlack escapes the characters Slack treats as control sequences.
+func escapeSlack(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Notify posts a summary of record to the Slack webhook.
+func (n SlackNotifier) Notify(record SessionRecord) error {
+	body, err := json.Marshal(slackMessageFor(record))
+	if err != nil {
+		return err
+	}
+	timeout := n.Timeout
+	if timeout <= 0 {
+		timeout = webhookTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Post(n.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack returned %s", resp.Status)
+	}
+	return nil
+}
+
+// notifySlack posts the session record to Slack when a webhook URL is
+// configured, reporting a failure as a warning.
+func (s *session) notifySlack() {
+	if s.cfg.Slack.Webhook == "" {
+		return
+	}
+	// Both end-of-session posts share webhook.timeout.
+	notifier := SlackNotifier{WebhookURL: s.cfg.Slack.Webhook, Timeout: s.cfg.Webhook.timeout()}
+	if err := notifier.Notify(*s.record); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: slack notification failed:", err)
+	}
+}
@@ -0,0 +1,46 @@
+package pomo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlackMessageFor(t *testing.T) {
+	msg := slackMessageFor(SessionRecord{
+		Title:          "Fix <bugs> & ship",
+		Goal:           "Close #12",
+		WorkTime:       50 * time.Minute,
+		CompletedPomos: 2,
+	})
+
+	if len(msg.Blocks) != 2 {
+		t.Fatalf("got %d blocks, want a heading and a fields section", len(msg.Blocks))
+	}
+	if got, want := msg.Blocks[0].Text.Text, "*Fix &lt;bugs&gt; &amp; ship* finished"; got != want {
+		t.Errorf("heading = %q, want %q", got, want)
+	}
+	fields := msg.Blocks[1].Fields
+	if len(fields) != 3 {
+		t.Fatalf("got %d fields, want pomodoros, work time and goal", len(fields))
+	}
+	if fields[2].Text != "*Goal*\nClose #12" {
+		t.Errorf("goal field = %q", fields[2].Text)
+	}
+	if !strings.Contains(msg.Text, "2 pomodoros, 50m0s of work") {
+		t.Errorf("fallback text = %q", msg.Text)
+	}
+}
+
+func TestSlackMessageForWithoutGoal(t *testing.T) {
+	msg := slackMessageFor(SessionRecord{CompletedPomos: 1})
+
+	if got := msg.Blocks[0].Text.Text; got != "*Pomodoro session finished*" {
+		t.Errorf("heading without a title = %q", got)
+	}
+	for _, field := range msg.Blocks[1].Fields {
+		if strings.HasPrefix(field.Text, "*Goal*") {
+			t.Errorf("goal field present without a goal: %q", field.Text)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package pomo
+
+import "time"
+
+// reminder is a notification due once an interval has run for at.
+type reminder struct {
+	at      time.Duration
+	message string
+}
+
+// reminders returns the notifications due during a work interval of d, in
+// the order they fire. Intervals of five minutes or less get none, and when
+// both fall at the same time only the halfway one is sent.
+func (s *session) reminders(d time.Duration) []reminder {
+	if !s.cfg.Reminders || !s.cfg.NotificationsEnabled || QuietMode || d <= 5*time.Minute {
+		return nil
+	}
+	halfway := reminder{d / 2, "Halfway there, keep going"}
+	fiveLeft := reminder{d - 5*time.Minute, "5 minutes left in this pomodoro"}
+	if fiveLeft.at == halfway.at {
+		return []reminder{halfway}
+	}
+	if fiveLeft.at < halfway.at {
+		return []reminder{fiveLeft, halfway}
+	}
+	return []reminder{halfway, fiveLeft}
+}
+
+// sendReminders notifies about every reminder that is now due and returns
+// the ones still pending, so each fires at most once per interval.
+func (s *session) sendReminders(interval *Timer, pending []reminder) []reminder {
+	for len(pending) > 0 && interval.Elapsed() >= pending[0].at {
+		s.notify(pending[0].message)
+		pending = pending[1:]
+	}
+	return pending
+}
+
+// dropPassed discards the reminders already due at elapsed, such as those
+// passed before a resumed interval was interrupted.
+func dropPassed(pending []reminder, elapsed time.Duration) []reminder {
+	for len(pending) > 0 && pending[0].at <= elapsed {
+		pending = pending[1:]
+	}
+	return pending
+}
@@ -0,0 +1,42 @@
+package pomo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminders(t *testing.T) {
+	s := &session{cfg: TimerConfig{Reminders: true, NotificationsEnabled: true}}
+	tests := []struct {
+		d    time.Duration
+		want []time.Duration
+	}{
+		{5 * time.Minute, nil},
+		{8 * time.Minute, []time.Duration{3 * time.Minute, 4 * time.Minute}},
+		{10 * time.Minute, []time.Duration{5 * time.Minute}},
+		{25 * time.Minute, []time.Duration{12*time.Minute + 30*time.Second, 20 * time.Minute}},
+	}
+	for _, tt := range tests {
+		got := s.reminders(tt.d)
+		if len(got) != len(tt.want) {
+			t.Errorf("reminders(%v) = %v, want times %v", tt.d, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i].at != tt.want[i] {
+				t.Errorf("reminders(%v)[%d].at = %v, want %v", tt.d, i, got[i].at, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestDropPassed(t *testing.T) {
+	s := &session{cfg: TimerConfig{Reminders: true, NotificationsEnabled: true}}
+	pending := dropPassed(s.reminders(25*time.Minute), 15*time.Minute)
+	if len(pending) != 1 || pending[0].at != 20*time.Minute {
+		t.Errorf("dropPassed at 15m = %v, want only the 5 minutes left reminder", pending)
+	}
+	if pending := dropPassed(s.reminders(25*time.Minute), 0); len(pending) != 2 {
+		t.Errorf("dropPassed at 0s = %v, want both reminders", pending)
+	}
+}
@@ -0,0 +1,33 @@
+package pomo
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// SessionRecord summarises one run of the timer. It is saved in
+// active_session.json, where WorkTime is in nanoseconds like the other
+// durations there.
+type SessionRecord struct {
+	Date            time.Time     `json:"date"`
+	Title           string        `json:"title"`
+	Goal            string        `json:"goal"`
+	WorkTime        time.Duration `json:"work_time"`
+	CompletedPomos  int           `json:"completed_pomos"`
+	SkippedSessions int           `json:"skipped_sessions"`
+}
+
+func printSessionSummary(w io.Writer, record *SessionRecord) {
+	fmt.Fprintln(w, Colorize("Session summary", ColorCyan))
+	if record.Title != "" {
+		fmt.Fprintf(w, "  Title:            %s\n", record.Title)
+	}
+	if record.Goal != "" {
+		fmt.Fprintf(w, "  Goal:             %s\n", record.Goal)
+	}
+	fmt.Fprintf(w, "  Started:          %s\n", record.Date.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "  Completed pomos:  %d\n", record.CompletedPomos)
+	fmt.Fprintf(w, "  Skipped sessions: %d\n", record.SkippedSessions)
+	fmt.Fprintf(w, "  Work time:        %v\n", record.WorkTime.Round(time.Second))
+}
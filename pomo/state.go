@@ -0,0 +1,167 @@
+package pomo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const stateFileName = "active_session.json"
+
+// How often a running timer snapshots its state.
+const stateSaveInterval = 5 * time.Second
+
+type Phase string
+
+const (
+	PhaseWork       Phase = "work"
+	PhaseShortBreak Phase = "short break"
+	PhaseLongBreak  Phase = "long break"
+	// PhaseWaiting is the pause between a break and the next pomodoro
+	// while the timer asks whether to carry on.
+	PhaseWaiting Phase = "waiting"
+)
+
+// SessionState is the snapshot of a running session written to
+// ~/.pomo/active_session.json. Other pomo processes read it to report on
+// the timer, and a snapshot left behind by a crash lets the session be
+// resumed.
+type SessionState struct {
+	PID       int           `json:"pid"`
+	Record    SessionRecord `json:"record"`
+	PomoCount int           `json:"pomo_count"`
+	Phase     Phase         `json:"phase"`
+	Duration  time.Duration `json:"duration"`
+	Elapsed   time.Duration `json:"elapsed"`
+	Remaining time.Duration `json:"remaining"`
+	Paused    bool          `json:"paused"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// ErrNoPomoDir is wrapped by errors caused by ~/.pomo being unavailable,
+// e.g. when $HOME is unset or read-only.
+var ErrNoPomoDir = errors.New("~/.pomo is unavailable")
+
+func pomoDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoPomoDir, err)
+	}
+	dir := filepath.Join(home, ".pomo")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoPomoDir, err)
+	}
+	return dir, nil
+}
+
+func statePath() (string, error) {
+	dir, err := pomoDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, stateFileName), nil
+}
+
+func SaveSessionState(state *SessionState) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so readers never see a half-written file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadSessionState returns nil without an error when there is no snapshot.
+func LoadSessionState() (*SessionState, error) {
+	path, err := statePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func ClearSessionState() error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// InterruptedSession returns the snapshot left behind by a session that
+// stopped without cleaning up, or nil if there is none.
+func InterruptedSession() (*SessionState, error) {
+	state, err := LoadSessionState()
+	if err != nil || state == nil {
+		return nil, err
+	}
+	if TimerRunning() {
+		return nil, nil
+	}
+	return state, nil
+}
+
+// ActiveSession returns the state of the timer running in another pomo
+// process, or nil if none is running.
+func ActiveSession() (*SessionState, error) {
+	state, err := LoadSessionState()
+	if err != nil || state == nil {
+		return nil, err
+	}
+	if !TimerRunning() {
+		return nil, nil
+	}
+	return state, nil
+}
+
+// RemainingAt estimates the time left in the current interval at now,
+// allowing for the time since the snapshot was written.
+func (s *SessionState) RemainingAt(now time.Time) time.Duration {
+	remaining := s.Remaining
+	if !s.Paused {
+		remaining -= now.Sub(s.UpdatedAt)
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Status is a one-line summary of the session for status bars, such as
+// "work 12:34 remaining, 2 pomodoros done".
+func (s *SessionState) Status(now time.Time) string {
+	if s.Phase == PhaseWaiting {
+		return fmt.Sprintf("waiting to start the next pomodoro, %d pomodoros done", s.Record.CompletedPomos)
+	}
+	status := "remaining"
+	if s.Paused {
+		status = "paused"
+	}
+	return fmt.Sprintf("%s %s %s, %d pomodoros done", s.Phase,
+		formatClock(s.RemainingAt(now)), status, s.Record.CompletedPomos)
+}
@@ -1,47 +1,567 @@
 package pomo
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gen2brain/beeep"
 )
 
-func alert(message string) {
-	if err := beeep.Alert("Pomodoro", message, "assets/information.png"); err != nil {
-		panic(err)
+// QuietMode suppresses all timer output, sounds and desktop notifications.
+var QuietMode bool
+
+type TimerConfig struct {
+	WorkDuration      time.Duration
+	ShortBreak        time.Duration
+	LongBreak         time.Duration
+	LongBreakInterval int
+	// CreatedAt and UpdatedAt record when timer.json was first and last
+	// saved.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	SoundEnabled         bool
+	NotificationsEnabled bool
+	// ShowProgress redraws a countdown line every second while an
+	// interval runs.
+	ShowProgress bool
+
+	// AutoStart begins each pomodoro after its break without asking.
+	AutoStart bool
+	// Reminders sends "halfway" and "5 minutes left" notifications during
+	// work intervals longer than five minutes.
+	Reminders bool
+
+	// OnPomodoro and OnBreak are shell commands run when a pomodoro or a
+	// break finishes. Skipped intervals run neither.
+	OnPomodoro string
+	OnBreak    string
+
+	// Title and Goal describe the session being started; they are recorded
+	// on the SessionRecord and never persisted with the config.
+	Title string
+	Goal  string
+	// Count stops the session after that many completed pomodoros without
+	// prompting. Zero asks whether to continue after every break.
+	Count int
+	// Webhook is POSTed the session record when the session ends.
+	Webhook WebhookConfig
+	// Slack is posted a summary of the session when it ends.
+	Slack SlackConfig
+
+	// Resume continues an interrupted session from its saved state.
+	Resume *SessionState
+}
+
+func DefaultTimerConfig() TimerConfig {
+	return TimerConfig{
+		WorkDuration:      25 * time.Minute,
+		ShortBreak:        5 * time.Minute,
+		LongBreak:         30 * time.Minute,
+		LongBreakInterval: 4,
+
+		SoundEnabled:         true,
+		NotificationsEnabled: true,
+		ShowProgress:         true,
+	}
+}
+
+func (c TimerConfig) Validate() error {
+	if c.WorkDuration <= 0 {
+		return errors.New("work duration must be greater than zero")
+	}
+	if c.ShortBreak <= 0 {
+		return errors.New("short break must be greater than zero")
+	}
+	if c.LongBreak <= 0 {
+		return errors.New("long break must be greater than zero")
+	}
+	if c.LongBreakInterval <= 0 {
+		return errors.New("long break interval must be greater than zero")
+	}
+	if c.Count < 0 {
+		return errors.New("count must not be negative")
+	}
+	return c.Webhook.Validate()
+}
+
+// alert shows a desktop notification and beeps, as enabled. A failure,
+// e.g. on a headless machine, is logged once and that kind of alert is
+// then turned off for the rest of the session.
+func (s *session) alert(message string) {
+	s.notify(message)
+	s.beep()
+}
+
+func (s *session) notify(message string) {
+	if QuietMode || !s.cfg.NotificationsEnabled {
+		return
+	}
+	if err := beeep.Notify("Pomodoro", message, "assets/information.png"); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: desktop notifications disabled:", err)
+		s.cfg.NotificationsEnabled = false
+	}
+}
+
+func (s *session) beep() {
+	if QuietMode || !s.cfg.SoundEnabled {
+		return
+	}
+	if err := beeep.Beep(beeep.DefaultFreq, beeep.DefaultDuration); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: sound disabled:", err)
+		s.cfg.SoundEnabled = false
+	}
+}
+
+func Run() error {
+	return RunWithConfig(DefaultTimerConfig())
+}
+
+func RunWithConfig(cfg TimerConfig) error {
+	return RunWithClock(cfg, RealClock)
+}
+
+// RunWithClock is RunWithConfig with the passage of time driven by clock.
+func RunWithClock(cfg TimerConfig, clock Clock) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Pause and resume from other terminals are optional; the timer runs
+	// without them when the control socket cannot be set up.
+	var requests <-chan controlRequest
+	control, err := listenControl()
+	if errors.Is(err, ErrTimerRunning) {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: pomo pause and pomo resume disabled:", err)
+	} else {
+		requests = control.requests
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	keys, echo, restoreTerminal := listenKeys()
+
+	// cleanup undoes the setup above. Signal handling stops last, so a
+	// Ctrl-C cannot kill pomo before the terminal, the session state and
+	// the socket are tidied up.
+	var cleanupOnce sync.Once
+	cleanup := func() {
+		cleanupOnce.Do(func() {
+			restoreTerminal()
+			ClearSessionState()
+			if control != nil {
+				control.Close()
+			}
+			signal.Stop(signals)
+		})
+	}
+	defer cleanup()
+
+	out := io.Writer(os.Stdout)
+	if QuietMode {
+		out = io.Discard
+		cfg.ShowProgress = false
+	}
+
+	s := &session{
+		cfg:   cfg,
+		clock: clock,
+		out:   out,
+		// Nobody can see the prompt in quiet mode or answer it without
+		// a keyboard, so like auto mode it keeps going until interrupted
+		// or the count is reached.
+		prompt:   cfg.Count == 0 && !cfg.AutoStart && !QuietMode && keys != nil,
+		requests: requests,
+		signals:  signals,
+		keys:     keys,
+		echo:     echo,
+		record:   &SessionRecord{Date: clock.Now(), Title: cfg.Title, Goal: cfg.Goal},
+	}
+	if cfg.Resume != nil {
+		record := cfg.Resume.Record
+		s.record = &record
+		s.pomoCount = cfg.Resume.PomoCount
+		s.resumed = cfg.Resume
 	}
+	err = s.run()
+	if err == nil || errors.Is(err, ErrInterrupted) {
+		fmt.Fprintln(s.out)
+		printSessionSummary(s.out, s.record)
+	}
+	// Let a second Ctrl-C kill pomo while the notifications post.
+	cleanup()
+	if err == nil || errors.Is(err, ErrInterrupted) {
+		s.fireWebhook()
+		s.notifySlack()
+	}
+	return err
 }
 
-func Run() {
+var ErrInterrupted = errors.New("pomodoro session interrupted")
+
+// errSkipped is returned by wait when the user skips the current interval.
+var errSkipped = errors.New("interval skipped")
+
+type session struct {
+	cfg    TimerConfig
+	clock  Clock
+	out    io.Writer
+	prompt bool
+	// requests is nil when the control socket is unavailable.
+	requests <-chan controlRequest
+	signals  chan os.Signal
+	keys     <-chan byte
+	// echo is set when keys are read unechoed and confirm must echo them.
+	echo      bool
+	pomoCount int
+	record    *SessionRecord
+	// resumed is the interrupted state to pick up from in the first
+	// interval.
+	resumed *SessionState
+	// stateDisabled stops saving snapshots after the first failure.
+	stateDisabled bool
+}
 
-	pomoCount := 0
+func (s *session) run() error {
+	cfg := s.cfg
 	carryOn := true
 
+	if s.record.Title != "" {
+		fmt.Fprintln(s.out, Colorize("Session:", ColorCyan), s.record.Title)
+	}
+	if s.record.Goal != "" {
+		fmt.Fprintln(s.out, Colorize("Goal:", ColorCyan), s.record.Goal)
+	}
+	if s.keys != nil {
+		fmt.Fprintln(s.out, "Press p to pause or resume, s to skip the current interval")
+	}
+
+	if s.resumed != nil && s.resumed.Phase != PhaseWork {
+		if s.resumed.Phase == PhaseWaiting {
+			s.resumed = nil
+		} else {
+			fmt.Fprintln(s.out, "Resuming the interrupted break")
+			if err := s.takeBreak(); err != nil {
+				return err
+			}
+		}
+		var err error
+		if carryOn, err = s.askToContinue(); err != nil {
+			return err
+		}
+	}
+
 	for carryOn == true {
-		fmt.Println("Starting pomodoro timer (25 minutes)")
-		alert("It's time to get into the flow")
+		if s.resumed != nil {
+			left := s.intervalLength(PhaseWork, cfg.WorkDuration) - s.resumed.Elapsed
+			fmt.Fprintf(s.out, "Resuming the interrupted pomodoro (%v left)\n", left.Round(time.Second))
+		} else {
+			fmt.Fprintf(s.out, "Starting pomodoro timer (%v)\n", cfg.WorkDuration)
+		}
+		s.alert("It's time to get into the flow")
 
-		time.Sleep(25 * time.Minute)
-		fmt.Println("End of pomodoro interval")
+		worked, err := s.wait(PhaseWork, cfg.WorkDuration)
+		s.record.WorkTime += worked
+		if errors.Is(err, errSkipped) {
+			s.record.SkippedSessions++
+			fmt.Fprintln(s.out, Colorize("Pomodoro skipped, starting the next one", ColorYellow))
+			continue
+		}
+		if err != nil {
+			// The pomodoro in progress was abandoned.
+			s.record.SkippedSessions++
+			return err
+		}
+		fmt.Fprintln(s.out, Colorize("End of pomodoro interval", ColorGreen))
 
-		pomoCount += 1
-		fmt.Println("Check Marks:", pomoCount)
+		s.pomoCount += 1
+		s.record.CompletedPomos++
+		fmt.Fprintln(s.out, "Check Marks:", s.pomoCount)
+		s.runHook(cfg.OnPomodoro, PhaseWork)
 
-		if pomoCount == 4 {
-			fmt.Println("Take a long breaktime - 30 minutes")
-			alert("Take a long break - 30 minutes")
-			time.Sleep(30 * time.Minute)
-			alert("30 minutes breaktime is over")
-			pomoCount = 0
-		} else {
-			fmt.Println("Take a short breaktime - 5 minutes")
-			alert("Take a short breaktime - 5 minutes")
-			time.Sleep(5 * time.Minute)
-			alert("5 minutes breaktime is over")
+		if cfg.Count > 0 && s.record.CompletedPomos >= cfg.Count {
+			fmt.Fprintln(s.out, Colorize(fmt.Sprintf("Completed %d pomodoros", cfg.Count), ColorGreen))
+			break
+		}
+
+		if err := s.takeBreak(); err != nil {
+			return err
+		}
+		if carryOn, err = s.askToContinue(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(s.out, "Good bye!")
+	return nil
+}
+
+// takeBreak runs a long break every LongBreakInterval pomodoros and a
+// short one otherwise. Like a skipped pomodoro, a skipped break does not
+// run its hook. An interrupted break resumes as the kind it was.
+func (s *session) takeBreak() error {
+	cfg := s.cfg
+	phase, d := PhaseShortBreak, cfg.ShortBreak
+	// A resumed session may have done more pomodoros than the current
+	// interval, hence >=.
+	long := s.pomoCount >= cfg.LongBreakInterval
+	if r := s.resumed; r != nil && (r.Phase == PhaseShortBreak || r.Phase == PhaseLongBreak) {
+		long = r.Phase == PhaseLongBreak
+	}
+	if long {
+		phase, d = PhaseLongBreak, cfg.LongBreak
+	}
+	d = s.intervalLength(phase, d)
+	if long {
+		fmt.Fprintf(s.out, "Take a long breaktime - %v\n", d)
+		s.alert(fmt.Sprintf("Take a long break - %v", d))
+	} else {
+		fmt.Fprintf(s.out, "Take a short breaktime - %v\n", d)
+		s.alert(fmt.Sprintf("Take a short breaktime - %v", d))
+	}
+	_, err := s.wait(phase, d)
+	if err != nil && !errors.Is(err, errSkipped) {
+		return err
+	}
+	if err == nil {
+		s.runHook(cfg.OnBreak, phase)
+	}
+	s.alert(fmt.Sprintf("%v breaktime is over", d))
+	if phase == PhaseLongBreak {
+		s.pomoCount = 0
+	}
+	return nil
+}
+
+// intervalLength returns d, or the saved length when phase continues an
+// interrupted interval, which may have run with other settings.
+func (s *session) intervalLength(phase Phase, d time.Duration) time.Duration {
+	if r := s.resumed; r != nil && r.Phase == phase && r.Duration > 0 {
+		return r.Duration
+	}
+	return d
+}
+
+// askToContinue reports whether to start another pomodoro, asking only
+// when the session is interactive.
+func (s *session) askToContinue() (bool, error) {
+	if !s.prompt {
+		return true, nil
+	}
+	s.saveState(PhaseWaiting, nil)
+	return s.confirm("Start another pomodoro? [Y/n] ")
+}
+
+// confirm asks a yes/no question on the keyboard, defaulting to yes. In
+// cbreak mode keys are read unechoed, so typed characters are echoed here.
+func (s *session) confirm(question string) (bool, error) {
+	fmt.Fprint(s.out, question)
+	var answer []byte
+	for {
+		select {
+		case <-s.signals:
+			return false, ErrInterrupted
+		case req := <-s.requests:
+			req.reply <- errors.New("timer is waiting at the continue prompt")
+		case key, ok := <-s.keys:
+			if !ok {
+				// No more input, carry on as if Enter was pressed.
+				s.keys = nil
+				fmt.Fprintln(s.out)
+				return true, nil
+			}
+			if key != '\n' && key != '\r' {
+				answer = append(answer, key)
+				if s.echo {
+					fmt.Fprintf(s.out, "%c", key)
+				}
+				continue
+			}
+			if s.echo {
+				fmt.Fprintln(s.out)
+			}
+			switch strings.ToLower(strings.TrimSpace(string(answer))) {
+			case "", "y", "yes":
+				return true, nil
+			case "n", "no":
+				return false, nil
+			}
+			fmt.Fprint(s.out, question)
+			answer = answer[:0]
 		}
+	}
+}
+
+// wait blocks for d of unpaused time, serving pause and resume requests
+// from the keyboard and from other pomo processes, and returns the time
+// spent running. It stops early with errSkipped when the user skips the
+// interval, or with ErrInterrupted when the process receives SIGINT or
+// SIGTERM.
+func (s *session) wait(phase Phase, d time.Duration) (time.Duration, error) {
+	d = s.intervalLength(phase, d)
+	interval := NewTimerWithClock(d, s.clock)
+	if r := s.resumed; r != nil {
+		s.resumed = nil
+		if r.Phase == phase {
+			interval.elapsed = r.Elapsed
+		}
+	}
+	done := s.clock.After(interval.Remaining())
+	s.saveState(phase, interval)
+	snapshot := s.clock.After(stateSaveInterval)
 
-		//Ask for input to set carryon as true or false
+	var reminders []reminder
+	if phase == PhaseWork {
+		reminders = dropPassed(s.reminders(d), interval.Elapsed())
+	}
+	tick := s.tick(len(reminders) > 0)
+	s.renderProgress(phase, interval)
+	defer s.clearProgress()
+
+	for {
+		select {
+		case <-done:
+			return d, nil
+		case <-snapshot:
+			s.saveState(phase, interval)
+			snapshot = s.clock.After(stateSaveInterval)
+		case <-tick:
+			s.renderProgress(phase, interval)
+			reminders = s.sendReminders(interval, reminders)
+			tick = s.tick(len(reminders) > 0)
+		case <-s.signals:
+			return interval.Elapsed(), ErrInterrupted
+		case key, ok := <-s.keys:
+			if !ok {
+				s.keys = nil
+				continue
+			}
+			switch key {
+			case keyPause:
+				if interval.Paused() {
+					done = s.resume(interval)
+				} else {
+					done = s.pause(interval)
+				}
+				s.saveState(phase, interval)
+				s.renderProgress(phase, interval)
+			case keySkip:
+				interval.Skip()
+				return interval.Elapsed(), errSkipped
+			}
+		case req := <-s.requests:
+			var err error
+			done, err = s.handleCommand(req.command, interval, done)
+			if err == nil {
+				s.saveState(phase, interval)
+				s.renderProgress(phase, interval)
+			}
+			req.reply <- err
+		}
+	}
+}
+
+// handleCommand applies a control command to interval and returns the
+// channel that fires when the interval completes, nil while paused.
+func (s *session) handleCommand(command string, interval *Timer, done <-chan time.Time) (<-chan time.Time, error) {
+	switch command {
+	case CommandPause:
+		if interval.Paused() {
+			return done, errors.New("timer is already paused")
+		}
+		return s.pause(interval), nil
+	case CommandResume:
+		if !interval.Paused() {
+			return done, errors.New("timer is not paused")
+		}
+		return s.resume(interval), nil
+	default:
+		return done, fmt.Errorf("unknown command %q", command)
+	}
+}
+
+func (s *session) pause(interval *Timer) <-chan time.Time {
+	interval.Pause()
+	s.clearProgress()
+	fmt.Fprintln(s.out, Colorize(fmt.Sprintf("Timer paused (%v remaining)", interval.Remaining().Round(time.Second)), ColorYellow))
+	return nil
+}
+
+func (s *session) resume(interval *Timer) <-chan time.Time {
+	interval.Resume()
+	s.clearProgress()
+	fmt.Fprintln(s.out, "Timer resumed")
+	return s.clock.After(interval.Remaining())
+}
+
+// tick returns a channel firing in a second when the countdown is shown
+// or reminders are pending, and nil otherwise.
+func (s *session) tick(pending bool) <-chan time.Time {
+	if !s.cfg.ShowProgress && !pending {
+		return nil
+	}
+	return s.clock.After(time.Second)
+}
+
+// renderProgress redraws the countdown in place on the current line.
+func (s *session) renderProgress(phase Phase, interval *Timer) {
+	if !s.cfg.ShowProgress {
+		return
+	}
+	status := "remaining"
+	if interval.Paused() {
+		status = "paused"
+	}
+	var label string
+	switch phase {
+	case PhaseWork:
+		label = fmt.Sprintf("Work – Pomo %d/%d", s.pomoCount+1, s.cfg.LongBreakInterval)
+	case PhaseShortBreak:
+		label = "Short break"
+	case PhaseLongBreak:
+		label = "Long break"
+	}
+	bar := RenderProgressBar(interval.Elapsed(), interval.duration, defaultProgressBarWidth)
+	fmt.Fprintf(s.out, "\r\033[K[%s %s] %s %s", formatClock(interval.Remaining()), status,
+		Colorize(bar, phaseColor(phase)), label)
+}
+
+func (s *session) clearProgress() {
+	if s.cfg.ShowProgress {
+		fmt.Fprint(s.out, "\r\033[K")
+	}
+}
+
+// saveState snapshots the session. interval is nil between intervals.
+func (s *session) saveState(phase Phase, interval *Timer) {
+	state := &SessionState{
+		PID:       os.Getpid(),
+		Record:    *s.record,
+		PomoCount: s.pomoCount,
+		Phase:     phase,
+		UpdatedAt: s.clock.Now(),
+	}
+	if interval != nil {
+		state.Duration = interval.duration
+		state.Elapsed = interval.Elapsed()
+		state.Remaining = interval.Remaining()
+		state.Paused = interval.Paused()
+	}
+	if s.stateDisabled {
+		return
+	}
+	if err := SaveSessionState(state); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: session state disabled:", err)
+		s.stateDisabled = true
 	}
-	fmt.Println("Good bye!")
 }
@@ -0,0 +1,170 @@
+package pomo
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testConfig keeps every interval shorter than stateSaveInterval, so the
+// only clock event during an interval is its end.
+func testConfig() TimerConfig {
+	return TimerConfig{
+		WorkDuration:      3 * time.Second,
+		ShortBreak:        time.Second,
+		LongBreak:         2 * time.Second,
+		LongBreakInterval: 2,
+	}
+}
+
+func newTestSession(t *testing.T, cfg TimerConfig) (*session, *fakeClock, chan byte, *bytes.Buffer) {
+	t.Helper()
+	setTestHome(t)
+
+	clock := newFakeClock()
+	clock.waits = make(chan time.Duration)
+	keys := make(chan byte)
+	var out bytes.Buffer
+	s := &session{
+		cfg:     cfg,
+		clock:   clock,
+		out:     &out,
+		signals: make(chan os.Signal, 1),
+		keys:    keys,
+		record:  &SessionRecord{Date: clock.Now()},
+	}
+	return s, clock, keys, &out
+}
+
+// drive runs the session, ending each interval in turn: the i-th one is
+// skipped when actions[i] is 's' and runs to the end otherwise. It returns
+// the length of every interval started.
+func drive(t *testing.T, s *session, clock *fakeClock, keys chan<- byte, actions string) []time.Duration {
+	t.Helper()
+	result := make(chan error, 1)
+	go func() { result <- s.run() }()
+
+	var intervals []time.Duration
+	timeout := time.After(5 * time.Second)
+	for {
+		var d time.Duration
+		select {
+		case d = <-clock.waits:
+		case err := <-result:
+			if err != nil {
+				t.Fatalf("run() = %v", err)
+			}
+			return intervals
+		case <-timeout:
+			t.Fatal("session did not finish")
+		}
+		// wait asks for the end of the interval, then for the next
+		// snapshot, before blocking.
+		<-clock.waits
+		i := len(intervals)
+		intervals = append(intervals, d)
+		if i < len(actions) && actions[i] == 's' {
+			keys <- keySkip
+		} else {
+			clock.advance(d)
+		}
+	}
+}
+
+func TestSessionSkip(t *testing.T) {
+	cfg := testConfig()
+	cfg.Count = 1
+	s, clock, keys, _ := newTestSession(t, cfg)
+
+	intervals := drive(t, s, clock, keys, "s")
+	if len(intervals) != 2 {
+		t.Fatalf("ran %d intervals, want the skipped pomodoro and one more", len(intervals))
+	}
+	if s.record.SkippedSessions != 1 {
+		t.Errorf("SkippedSessions = %d, want 1", s.record.SkippedSessions)
+	}
+	if s.record.CompletedPomos != 1 {
+		t.Errorf("CompletedPomos = %d, want 1", s.record.CompletedPomos)
+	}
+	if s.record.WorkTime != 3*time.Second {
+		t.Errorf("WorkTime = %v, want 3s", s.record.WorkTime)
+	}
+}
+
+func TestSessionLongBreak(t *testing.T) {
+	cfg := testConfig()
+	cfg.Count = 3
+	s, clock, keys, out := newTestSession(t, cfg)
+
+	intervals := drive(t, s, clock, keys, "")
+	want := []time.Duration{3 * time.Second, time.Second, 3 * time.Second, 2 * time.Second, 3 * time.Second}
+	if !equalDurations(intervals, want) {
+		t.Errorf("intervals = %v, want %v", intervals, want)
+	}
+	if n := strings.Count(out.String(), "Take a long breaktime"); n != 1 {
+		t.Errorf("took %d long breaks, want 1", n)
+	}
+	if s.pomoCount != 1 {
+		t.Errorf("pomoCount = %d, want 1 after the long break reset it", s.pomoCount)
+	}
+}
+
+func TestSessionCountStops(t *testing.T) {
+	cfg := testConfig()
+	cfg.Count = 2
+	s, clock, keys, out := newTestSession(t, cfg)
+
+	intervals := drive(t, s, clock, keys, "")
+	if len(intervals) != 3 {
+		t.Errorf("ran %d intervals, want 2 pomodoros and the break between them", len(intervals))
+	}
+	if s.record.CompletedPomos != 2 {
+		t.Errorf("CompletedPomos = %d, want 2", s.record.CompletedPomos)
+	}
+	if !strings.Contains(out.String(), "Completed 2 pomodoros") {
+		t.Errorf("output does not report the count:\n%s", out.String())
+	}
+}
+
+func TestSessionResume(t *testing.T) {
+	cfg := testConfig()
+	cfg.Count = 6
+	s, clock, keys, out := newTestSession(t, cfg)
+	// Interrupted 1s into a 4s pomodoro, after more pomodoros than the
+	// current long break interval.
+	s.resumed = &SessionState{
+		Record:    SessionRecord{CompletedPomos: 4},
+		PomoCount: 3,
+		Phase:     PhaseWork,
+		Duration:  4 * time.Second,
+		Elapsed:   time.Second,
+	}
+	s.record = &s.resumed.Record
+	s.pomoCount = s.resumed.PomoCount
+
+	intervals := drive(t, s, clock, keys, "")
+	want := []time.Duration{3 * time.Second, 2 * time.Second, 3 * time.Second}
+	if !equalDurations(intervals, want) {
+		t.Errorf("intervals = %v, want the rest of the pomodoro, a long break and a pomodoro %v", intervals, want)
+	}
+	if !strings.Contains(out.String(), "Resuming the interrupted pomodoro (3s left)") {
+		t.Errorf("output does not report the resume:\n%s", out.String())
+	}
+	if s.record.WorkTime != 7*time.Second {
+		t.Errorf("WorkTime = %v, want 7s", s.record.WorkTime)
+	}
+}
+
+func equalDurations(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package pomo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const timerConfigFileName = "timer.json"
+
+// timerConfigFile is the on-disk form of the settings in timer.json, the
+// older settings file that LoadConfig falls back to. Durations are kept as
+// strings such as "25m0s" so the file stays readable and hand-editable.
+type timerConfigFile struct {
+	WorkDuration      string    `json:"work_duration"`
+	ShortBreak        string    `json:"short_break"`
+	LongBreak         string    `json:"long_break"`
+	LongBreakInterval int       `json:"long_break_interval"`
+	OnPomodoro        string    `json:"on_pomodoro,omitempty"`
+	OnBreak           string    `json:"on_break,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func newTimerConfigFile(cfg *TimerConfig) timerConfigFile {
+	return timerConfigFile{
+		WorkDuration:      cfg.WorkDuration.String(),
+		ShortBreak:        cfg.ShortBreak.String(),
+		LongBreak:         cfg.LongBreak.String(),
+		LongBreakInterval: cfg.LongBreakInterval,
+		OnPomodoro:        cfg.OnPomodoro,
+		OnBreak:           cfg.OnBreak,
+		CreatedAt:         cfg.CreatedAt,
+		UpdatedAt:         cfg.UpdatedAt,
+	}
+}
+
+// apply copies the settings and timestamps in f over cfg.
+func (f timerConfigFile) apply(cfg *TimerConfig) error {
+	var err error
+	if cfg.WorkDuration, err = time.ParseDuration(f.WorkDuration); err != nil {
+		return err
+	}
+	if cfg.ShortBreak, err = time.ParseDuration(f.ShortBreak); err != nil {
+		return err
+	}
+	if cfg.LongBreak, err = time.ParseDuration(f.LongBreak); err != nil {
+		return err
+	}
+	cfg.LongBreakInterval = f.LongBreakInterval
+	cfg.OnPomodoro = f.OnPomodoro
+	cfg.OnBreak = f.OnBreak
+	cfg.CreatedAt = f.CreatedAt
+	cfg.UpdatedAt = f.UpdatedAt
+	return nil
+}
+
+func timerConfigPath() (string, error) {
+	dir, err := pomoDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, timerConfigFileName), nil
+}
+
+// LoadTimerConfig reads ~/.pomo/timer.json, falling back to the
+// traditional 25/5/30 defaults when the file does not exist.
+func LoadTimerConfig() (*TimerConfig, error) {
+	path, err := timerConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		cfg := DefaultTimerConfig()
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f timerConfigFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	cfg := DefaultTimerConfig()
+	if err := f.apply(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SaveTimerConfig writes the file settings of cfg to ~/.pomo/timer.json,
+// stamping CreatedAt on the first save and UpdatedAt on every save.
+func SaveTimerConfig(cfg *TimerConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	path, err := timerConfigPath()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if cfg.CreatedAt.IsZero() {
+		cfg.CreatedAt = now
+	}
+	cfg.UpdatedAt = now
+	data, err := json.MarshalIndent(newTimerConfigFile(cfg), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
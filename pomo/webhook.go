@@ -0,0 +1,119 @@
+package pomo
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// webhookTimeout bounds how long the end of a session waits for the
+// webhook to answer when WebhookConfig.Timeout is not set.
+const webhookTimeout = 5 * time.Second
+
+// WebhookConfig describes the HTTP endpoint notified when a session ends.
+type WebhookConfig struct {
+	URL     string        `yaml:"url" toml:"url"`
+	Secret  string        `yaml:"secret" toml:"secret"`
+	Enabled bool          `yaml:"enabled" toml:"enabled"`
+	Timeout time.Duration `yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+}
+
+func (c WebhookConfig) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return webhookTimeout
+}
+
+// Validate reports an unusable URL on an enabled webhook or a negative
+// timeout.
+func (c WebhookConfig) Validate() error {
+	if c.Timeout < 0 {
+		return errors.New("webhook timeout must not be negative")
+	}
+	if !c.Enabled {
+		return nil
+	}
+	if c.URL == "" {
+		return errors.New("webhook is enabled but webhook.url is not set")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook url must be http or https, not %q", c.URL)
+	}
+	return nil
+}
+
+// webhookPayload is the JSON body posted to the webhook.
+type webhookPayload struct {
+	Date            time.Time `json:"date"`
+	Title           string    `json:"title"`
+	Goal            string    `json:"goal"`
+	WorkTime        string    `json:"work_time"`
+	WorkTimeSeconds int64     `json:"work_time_seconds"`
+	CompletedPomos  int       `json:"completed_pomos"`
+	SkippedSessions int       `json:"skipped_sessions"`
+}
+
+// FireWebhook POSTs record as JSON to the configured URL. With a secret
+// set, the body is signed with HMAC-SHA256 and the hex digest sent as
+// "sha256=<digest>" in the X-Pomo-Signature header. A disabled webhook is
+// a no-op.
+func FireWebhook(ctx context.Context, config *WebhookConfig, record SessionRecord) error {
+	if config == nil || !config.Enabled || config.URL == "" {
+		return nil
+	}
+	body, err := json.Marshal(webhookPayload{
+		Date:            record.Date,
+		Title:           record.Title,
+		Goal:            record.Goal,
+		WorkTime:        record.WorkTime.Round(time.Second).String(),
+		WorkTimeSeconds: int64(record.WorkTime / time.Second),
+		CompletedPomos:  record.CompletedPomos,
+		SkippedSessions: record.SkippedSessions,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(config.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Pomo-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// fireWebhook sends the session record to the webhook, if any, reporting
+// a failure as a warning.
+func (s *session) fireWebhook() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Webhook.timeout())
+	defer cancel()
+	if err := FireWebhook(ctx, &s.cfg.Webhook, *s.record); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: webhook failed:", err)
+	}
+}
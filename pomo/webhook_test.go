@@ -0,0 +1,107 @@
+package pomo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testRecord = SessionRecord{
+	Date:            time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+	Title:           "Write report",
+	Goal:            "First draft",
+	WorkTime:        50 * time.Minute,
+	CompletedPomos:  2,
+	SkippedSessions: 1,
+}
+
+func TestFireWebhook(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Pomo-Signature")
+	}))
+	defer server.Close()
+
+	config := &WebhookConfig{URL: server.URL, Secret: "s3cret", Enabled: true}
+	if err := FireWebhook(context.Background(), config, testRecord); err != nil {
+		t.Fatalf("FireWebhook() = %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("X-Pomo-Signature = %q, want %q", signature, want)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	want := map[string]interface{}{
+		"date":              "2024-01-01T09:00:00Z",
+		"title":             "Write report",
+		"goal":              "First draft",
+		"work_time":         "50m0s",
+		"work_time_seconds": 3000.0,
+		"completed_pomos":   2.0,
+		"skipped_sessions":  1.0,
+	}
+	for key, value := range want {
+		if payload[key] != value {
+			t.Errorf("payload[%q] = %v, want %v", key, payload[key], value)
+		}
+	}
+}
+
+func TestFireWebhookUnsigned(t *testing.T) {
+	signed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, signed = r.Header["X-Pomo-Signature"]
+	}))
+	defer server.Close()
+
+	config := &WebhookConfig{URL: server.URL, Enabled: true}
+	if err := FireWebhook(context.Background(), config, testRecord); err != nil {
+		t.Fatalf("FireWebhook() = %v", err)
+	}
+	if signed {
+		t.Error("request without a secret was signed")
+	}
+}
+
+func TestFireWebhookErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := &WebhookConfig{URL: server.URL, Enabled: true}
+	if err := FireWebhook(context.Background(), config, testRecord); err == nil {
+		t.Error("FireWebhook() = nil for a 500 response, want an error")
+	}
+}
+
+func TestFireWebhookDisabled(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	config := &WebhookConfig{URL: server.URL}
+	if err := FireWebhook(context.Background(), config, testRecord); err != nil {
+		t.Fatalf("FireWebhook() = %v", err)
+	}
+	if called {
+		t.Error("disabled webhook was posted to")
+	}
+}